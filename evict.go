@@ -0,0 +1,42 @@
+package pool
+
+// EvictReason identifies why a conn was removed from the pool, passed to
+// Config.OnEvict so callers can emit metrics or logs.
+type EvictReason int
+
+const (
+	// EvictIdle means the conn exceeded Config.MaxIdleTime.
+	EvictIdle EvictReason = iota
+	// EvictLifetime means the conn exceeded Config.MaxConnLifetime.
+	EvictLifetime
+	// EvictUseCount means the conn reached Config.MaxUseCount borrows.
+	EvictUseCount
+	// EvictHealthCheck means Config.ValidateFunc rejected the conn, or the
+	// periodic keepalive pass (Config.KeepAliveFunc) did not put it back
+	// into the idle store.
+	EvictHealthCheck
+	// EvictPoolFull means Put discarded the conn because the idle store
+	// was already at Config.MaxConns.
+	EvictPoolFull
+	// EvictClose means the conn was freed as part of Pool.Close.
+	EvictClose
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictIdle:
+		return "idle"
+	case EvictLifetime:
+		return "lifetime"
+	case EvictUseCount:
+		return "use-count"
+	case EvictHealthCheck:
+		return "health-check"
+	case EvictPoolFull:
+		return "pool-full"
+	case EvictClose:
+		return "close"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,520 @@
+package pool
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// genericItem is the generic counterpart of poolItem. createdAt and
+// useCount are snapshots copied from the conn's connMeta each time it is
+// put back, so idle-store scans (the reaper, ReapOnBorrow) can check
+// MaxConnLifetime/MaxUseCount without a map lookup per item.
+type genericItem[T any] struct {
+	val        T
+	accessTime time.Time
+	createdAt  time.Time
+	useCount   uint64
+	meta       any
+}
+
+// connMeta is the long-lived, per-conn bookkeeping that must survive a
+// conn being checked out of the pool (and so can't live on genericItem
+// alone, since a fresh genericItem is built around the conn on every
+// Put). It is keyed by the conn's own value in GenericPool.metas, which is
+// why values without real identity (e.g. a plain struct or slice compared
+// by content rather than a pointer) only get best-effort tracking: two
+// live values that happen to compare equal would share a connMeta entry.
+// Pass pointer-like types (e.g. *bytes.Buffer, not bytes.Buffer) to avoid
+// that. Values whose dynamic type can't even be used as a map key (e.g. an
+// IConn implementation that embeds a slice or map field by value) are
+// simply not tracked at all; see isTrackable.
+type connMeta struct {
+	createdAt time.Time
+	useCount  uint64
+	meta      any
+}
+
+// isTrackable reports whether val is safe to use as a key in
+// GenericPool.metas. Map indexing and comparison panic at runtime if the
+// *dynamic* type underneath val (which may be boxed in an interface, as
+// with IConn) isn't comparable — a slice or map field, say — even though
+// T itself satisfies Go's static comparable constraint trivially for any
+// interface type. Values that fail this check still work with the pool;
+// they just don't get createdAt/useCount/Meta tracking.
+func isTrackable(val any) bool {
+	t := reflect.TypeOf(val)
+	return t != nil && t.Comparable()
+}
+
+// GenericPool is a type-safe pool of arbitrary values, for callers who
+// want to pool plain objects (buffers, parsers, protobuf messages)
+// without paying for an IConn interface allocation on every Get. Pool is
+// built on top of GenericPool[IConn]; see NewGenericPool.
+type GenericPool[T any] struct {
+	config *Config
+	conns  *idleStore[T]
+
+	factory func() (T, error)
+	reset   func(T)
+	destroy func(T)
+
+	liveConns int32
+	waiting   int32
+	timeouts  uint64
+
+	waitMu sync.Mutex
+	waitCh chan struct{}
+
+	closed       int32
+	closeOnce    sync.Once
+	closeTimeout int64 // time.Duration, accessed atomically
+	done         chan struct{}
+
+	metaMu sync.Mutex
+	metas  map[any]*connMeta
+}
+
+// NewGenericPool creates a type-safe pool. factory produces new values,
+// reset is called on every Put to an open pool (analogous to sync.Pool-style hygiene,
+// e.g. truncating a buffer), and destroy replaces the IConn.Free hook for
+// values that are discarded instead of returned to the pool.
+//
+// Live values should ideally have real identity rather than just
+// comparing equal by content — pass pointer-like types (e.g.
+// *bytes.Buffer rather than bytes.Buffer) — so that createdAt/useCount/
+// Meta reliably survive a value being checked out of the pool and back
+// via Put. Values whose type can't safely be used as a map key at all
+// (e.g. a by-value IConn holding a slice or map field) still work; they
+// just never get that bookkeeping, per isTrackable.
+func NewGenericPool[T any](cfg *Config, factory func() (T, error), reset func(T), destroy func(T)) *GenericPool[T] {
+	p := &GenericPool[T]{
+		config:  cfg,
+		conns:   newIdleStore[T](cfg.MaxConns, cfg.PoolFIFO),
+		factory: factory,
+		reset:   reset,
+		destroy: destroy,
+		waitCh:  make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	if cfg.KeepAliveInterval > 0 && cfg.KeepAliveFunc != nil {
+		go p.keepAliveRoutine()
+	}
+
+	if cfg.MaxIdleTime > 0 {
+		go p.reapRoutine()
+	}
+
+	return p
+}
+
+func (p *GenericPool[T]) Get() (T, error) {
+	if p.isClosed() {
+		var zero T
+		return zero, ErrPoolClosed
+	}
+	if gi, ok := p.getValid(); ok {
+		return gi.val, nil
+	}
+	return p.newItem()
+}
+
+func (p *GenericPool[T]) GetContext(ctx context.Context) (T, error) {
+	for {
+		if p.isClosed() {
+			var zero T
+			return zero, ErrPoolClosed
+		}
+
+		if gi, ok := p.getValid(); ok {
+			return gi.val, nil
+		}
+
+		if live := atomic.LoadInt32(&p.liveConns); int(live) < p.config.MaxConns {
+			if !atomic.CompareAndSwapInt32(&p.liveConns, live, live+1) {
+				continue
+			}
+			val, err := p.factory()
+			if err != nil {
+				atomic.AddInt32(&p.liveConns, -1)
+				var zero T
+				return zero, err
+			}
+			p.trackNew(val)
+			return val, nil
+		}
+
+		if p.config.MaxWaiters > 0 && int(atomic.LoadInt32(&p.waiting)) >= p.config.MaxWaiters {
+			var zero T
+			return zero, ErrPoolExhausted
+		}
+
+		atomic.AddInt32(&p.waiting, 1)
+		err := p.wait(ctx)
+		atomic.AddInt32(&p.waiting, -1)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+}
+
+func (p *GenericPool[T]) wait(ctx context.Context) error {
+	p.waitMu.Lock()
+	ch := p.waitCh
+	p.waitMu.Unlock()
+
+	// A Put between the caller's last check and taking ch above has
+	// already signalled; return so the caller retries instead of missing it.
+	if p.conns.len() > 0 || int(atomic.LoadInt32(&p.liveConns)) < p.config.MaxConns {
+		return nil
+	}
+
+	var timeoutCh <-chan time.Time
+	if p.config.WaitTimeout > 0 {
+		timer := time.NewTimer(p.config.WaitTimeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+
+	select {
+	case <-ch:
+		return nil
+	case <-p.done:
+		return ErrPoolClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timeoutCh:
+		atomic.AddUint64(&p.timeouts, 1)
+		return context.DeadlineExceeded
+	}
+}
+
+func (p *GenericPool[T]) notifyWaiters() {
+	p.waitMu.Lock()
+	close(p.waitCh)
+	p.waitCh = make(chan struct{})
+	p.waitMu.Unlock()
+}
+
+func (p *GenericPool[T]) Put(val T) error {
+	if p.isClosed() {
+		p.destroyVal(val, EvictClose)
+		return ErrPoolClosed
+	}
+
+	if p.reset != nil {
+		p.reset(val)
+	}
+
+	gi := &genericItem[T]{
+		val:        val,
+		accessTime: time.Now(),
+	}
+	if cm := p.metaFor(val); cm != nil {
+		gi.createdAt = cm.createdAt
+		gi.useCount = atomic.LoadUint64(&cm.useCount)
+		gi.meta = cm.meta
+	}
+
+	notFull := p.put(gi)
+	if notFull {
+		p.notifyWaiters()
+		return nil
+	}
+
+	p.destroyVal(val, EvictPoolFull)
+	p.notifyWaiters()
+
+	return ErrPoolIsFull
+}
+
+func (p *GenericPool[T]) Stats() Stats {
+	return Stats{
+		TotalConns: atomic.LoadInt32(&p.liveConns),
+		IdleConns:  int32(p.conns.len()),
+		Waiting:    atomic.LoadInt32(&p.waiting),
+		Timeouts:   atomic.LoadUint64(&p.timeouts),
+	}
+}
+
+func (p *GenericPool[T]) get() *genericItem[T] {
+	gi, ok := p.conns.pop()
+	if !ok {
+		return nil
+	}
+	return gi
+}
+
+func (p *GenericPool[T]) put(gi *genericItem[T]) bool {
+	return p.conns.push(gi)
+}
+
+// getValid pulls idle items off the pool until it finds one that is
+// neither stale (older than MaxIdleTime) nor rejected by Config.ValidateFunc,
+// trying at most MaxValidateAttempts items (or, with ReapOnBorrow, as many
+// stale items as it finds along the way). It returns ok=false once the
+// idle list is empty or the attempt budget runs out, or, without
+// ReapOnBorrow, as soon as it discards a stale item.
+func (p *GenericPool[T]) getValid() (*genericItem[T], bool) {
+	maxAttempts := p.config.MaxValidateAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		gi := p.get()
+		if gi == nil {
+			return nil, false
+		}
+
+		if time.Now().Sub(gi.accessTime) >= p.config.MaxIdleTime {
+			p.destroyItem(gi, EvictIdle)
+			if p.config.ReapOnBorrow {
+				attempt--
+				continue
+			}
+			return nil, false
+		}
+
+		if err := p.validate(gi.val); err != nil {
+			p.destroyItem(gi, EvictHealthCheck)
+			continue
+		}
+
+		if p.config.MaxConnLifetime > 0 && time.Now().Sub(gi.createdAt) >= p.config.MaxConnLifetime {
+			p.destroyItem(gi, EvictLifetime)
+			continue
+		}
+		if p.config.MaxUseCount > 0 && gi.useCount >= p.config.MaxUseCount {
+			p.destroyItem(gi, EvictUseCount)
+			continue
+		}
+
+		if cm := p.metaFor(gi.val); cm != nil {
+			atomic.AddUint64(&cm.useCount, 1)
+		}
+
+		return gi, true
+	}
+
+	return nil, false
+}
+
+// validate runs Config.ValidateFunc against val when val implements IConn.
+func (p *GenericPool[T]) validate(val T) error {
+	if p.config.ValidateFunc == nil {
+		return nil
+	}
+	conn, ok := any(val).(IConn)
+	if !ok {
+		return nil
+	}
+	return p.config.ValidateFunc(conn)
+}
+
+func (p *GenericPool[T]) newItem() (T, error) {
+	val, err := p.factory()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	atomic.AddInt32(&p.liveConns, 1)
+	p.trackNew(val)
+	return val, nil
+}
+
+// trackNew registers fresh per-conn bookkeeping for val and counts the
+// conn being handed out as its first use. It is a no-op for values that
+// aren't isTrackable, so a badly-shaped val can't crash the pool.
+func (p *GenericPool[T]) trackNew(val T) {
+	if !isTrackable(val) {
+		return
+	}
+	cm := &connMeta{createdAt: time.Now(), useCount: 1}
+	p.metaMu.Lock()
+	if p.metas == nil {
+		p.metas = make(map[any]*connMeta)
+	}
+	p.metas[val] = cm
+	p.metaMu.Unlock()
+}
+
+func (p *GenericPool[T]) metaFor(val T) *connMeta {
+	if !isTrackable(val) {
+		return nil
+	}
+	p.metaMu.Lock()
+	cm := p.metas[val]
+	p.metaMu.Unlock()
+	return cm
+}
+
+func (p *GenericPool[T]) forgetMeta(val T) {
+	if !isTrackable(val) {
+		return
+	}
+	p.metaMu.Lock()
+	delete(p.metas, val)
+	p.metaMu.Unlock()
+}
+
+func (p *GenericPool[T]) destroyItem(gi *genericItem[T], reason EvictReason) {
+	p.destroyVal(gi.val, reason)
+}
+
+func (p *GenericPool[T]) destroyVal(val T, reason EvictReason) {
+	if p.destroy != nil {
+		p.destroy(val)
+	}
+	atomic.AddInt32(&p.liveConns, -1)
+	p.forgetMeta(val)
+
+	if p.config.OnEvict != nil {
+		if conn, ok := any(val).(IConn); ok {
+			p.config.OnEvict(conn, reason)
+		}
+	}
+}
+
+func (p *GenericPool[T]) keepAliveRoutine() {
+	ticker := time.NewTicker(p.config.KeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.keepAlive()
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// keepAlive mirrors Pool's original keepAlive. Config.KeepAliveFunc is an
+// IConn-specific hook, so it only fires for values that happen to
+// implement IConn (in practice, the Pool wrapper in pool.go); other
+// GenericPool instances skip straight to the reap below.
+func (p *GenericPool[T]) keepAlive() {
+	maxConns := p.conns.len()
+
+	for i := 0; i < maxConns; i++ {
+		gi := p.get()
+		if gi != nil {
+			if time.Now().Sub(gi.accessTime) < p.config.KeepAliveInterval {
+				if conn, ok := any(gi.val).(IConn); ok && p.config.KeepAliveFunc != nil {
+					err := p.config.KeepAliveFunc(conn)
+					if err != nil {
+						if p.put(gi) {
+							continue
+						}
+					}
+				}
+			}
+
+			p.destroyItem(gi, EvictHealthCheck)
+		}
+	}
+	p.notifyWaiters()
+}
+
+func (p *GenericPool[T]) CloseTimeout(d time.Duration) {
+	atomic.StoreInt64(&p.closeTimeout, int64(d))
+}
+
+func (p *GenericPool[T]) isClosed() bool {
+	return atomic.LoadInt32(&p.closed) == 1
+}
+
+func (p *GenericPool[T]) Close() error {
+	p.closeOnce.Do(func() {
+		atomic.StoreInt32(&p.closed, 1)
+		close(p.done)
+		p.notifyWaiters()
+		p.drain()
+		p.waitForWaiters()
+	})
+	return nil
+}
+
+func (p *GenericPool[T]) drain() {
+	for _, gi := range p.conns.drain() {
+		p.destroyItem(gi, EvictClose)
+	}
+}
+
+// reapRoutine periodically evicts idle items that have exceeded
+// MaxIdleTime, MaxConnLifetime or MaxUseCount, down to the MinIdleConns
+// floor.
+func (p *GenericPool[T]) reapRoutine() {
+	ticker := time.NewTicker(p.config.MaxIdleTime)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.reapStale()
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// lifetimeExpired reports whether gi has outlived MaxConnLifetime or
+// MaxUseCount, consistent with the same checks in getValid.
+func (p *GenericPool[T]) lifetimeExpired(gi *genericItem[T]) bool {
+	if p.config.MaxConnLifetime > 0 && time.Now().Sub(gi.createdAt) >= p.config.MaxConnLifetime {
+		return true
+	}
+	if p.config.MaxUseCount > 0 && gi.useCount >= p.config.MaxUseCount {
+		return true
+	}
+	return false
+}
+
+// evictReasonFor picks the reason reapStale reports for a lifetimeExpired
+// item it is about to remove.
+func (p *GenericPool[T]) evictReasonFor(gi *genericItem[T]) EvictReason {
+	if p.config.MaxConnLifetime > 0 && time.Now().Sub(gi.createdAt) >= p.config.MaxConnLifetime {
+		return EvictLifetime
+	}
+	return EvictUseCount
+}
+
+// reapStale evicts idle items past MaxIdleTime (cheaply, via reapIdle's
+// early break, since those accumulate at the stale end of the store) and
+// separately sweeps the whole store for items past MaxConnLifetime or
+// MaxUseCount, which can be anywhere in the store regardless of idle time.
+func (p *GenericPool[T]) reapStale() {
+	idleEvict := func(gi *genericItem[T]) bool {
+		return time.Now().Sub(gi.accessTime) >= p.config.MaxIdleTime
+	}
+	for _, gi := range p.conns.reapIdle(idleEvict, p.config.MinIdleConns) {
+		p.destroyItem(gi, EvictIdle)
+	}
+
+	if p.config.MaxConnLifetime > 0 || p.config.MaxUseCount > 0 {
+		for _, gi := range p.conns.reapWhere(p.lifetimeExpired, p.config.MinIdleConns) {
+			p.destroyItem(gi, p.evictReasonFor(gi))
+		}
+	}
+}
+
+func (p *GenericPool[T]) waitForWaiters() {
+	timeout := time.Duration(atomic.LoadInt64(&p.closeTimeout))
+
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	for atomic.LoadInt32(&p.waiting) > 0 {
+		if timeout > 0 && time.Now().After(deadline) {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,217 @@
+package pool
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingConn struct {
+	fakeConn
+	resets int32
+}
+
+// badConnByValue is an IConn implementation held by value whose dynamic
+// type is not comparable (it embeds a slice), the shape that used to make
+// trackNew panic on "hash of unhashable type" once boxed in IConn.
+type badConnByValue struct {
+	data []byte
+}
+
+func (badConnByValue) Free() {}
+
+func TestGenericPoolResetOnPutDestroyWhenFull(t *testing.T) {
+	var destroyed int32
+	p := NewGenericPool[*countingConn](
+		&Config{MaxConns: 1, MaxIdleTime: time.Minute},
+		func() (*countingConn, error) { return &countingConn{}, nil },
+		func(c *countingConn) { atomic.AddInt32(&c.resets, 1) },
+		func(c *countingConn) { atomic.AddInt32(&destroyed, 1) },
+	)
+
+	a, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if err := p.Put(a); err != nil {
+		t.Fatalf("Put a: %v", err)
+	}
+	if atomic.LoadInt32(&a.resets) != 1 {
+		t.Fatalf("resets = %d, want 1", a.resets)
+	}
+
+	b, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if b != a {
+		t.Fatalf("expected Get to hand back the same conn that was Put")
+	}
+
+	// Put a second, distinct conn while the store (cap 1) already holds
+	// room for only one idle item; Put b first so the store is occupied,
+	// then the extra conn should be destroyed instead of silently
+	// overwriting b's bookkeeping.
+	if err := p.Put(b); err != nil {
+		t.Fatalf("Put b: %v", err)
+	}
+	extra := &countingConn{}
+	if err := p.Put(extra); err != ErrPoolIsFull {
+		t.Fatalf("Put extra: expected ErrPoolIsFull, got %v", err)
+	}
+	if atomic.LoadInt32(&destroyed) != 1 {
+		t.Fatalf("destroyed = %d, want 1", destroyed)
+	}
+}
+
+func TestGenericPoolDistinctConnsDontShareMeta(t *testing.T) {
+	p := NewGenericPool[*countingConn](
+		&Config{MaxConns: 2, MaxIdleTime: time.Minute},
+		func() (*countingConn, error) { return &countingConn{}, nil },
+		nil,
+		func(*countingConn) {},
+	)
+
+	a, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get a: %v", err)
+	}
+	b, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get b: %v", err)
+	}
+	if a == b {
+		t.Fatalf("expected two distinct conns from two Gets under MaxConns 2")
+	}
+
+	// Round-trip only b a few times; a's metadata must not be touched.
+	for i := 0; i < 3; i++ {
+		if err := p.Put(b); err != nil {
+			t.Fatalf("Put b: %v", err)
+		}
+		b, err = p.Get()
+		if err != nil {
+			t.Fatalf("Get b: %v", err)
+		}
+	}
+
+	if cm := p.metaFor(a); cm == nil || cm.useCount != 1 {
+		t.Fatalf("a's useCount changed by cycling b: got %+v", cm)
+	}
+	if cm := p.metaFor(b); cm == nil || cm.useCount < 2 {
+		t.Fatalf("b's useCount did not track its own borrows: got %+v", cm)
+	}
+}
+
+func TestGenericPoolMaxUseCountEvicts(t *testing.T) {
+	var reasons []EvictReason
+	p := NewGenericPool[*fakeConn](
+		&Config{
+			MaxConns:    1,
+			MaxIdleTime: time.Minute,
+			MaxUseCount: 2,
+			OnEvict: func(conn IConn, reason EvictReason) {
+				reasons = append(reasons, reason)
+			},
+		},
+		func() (*fakeConn, error) { return &fakeConn{}, nil },
+		nil,
+		func(*fakeConn) {},
+	)
+
+	first, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if err := p.Put(first); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	second, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if second != first {
+		t.Fatalf("expected the same conn back below MaxUseCount")
+	}
+	if err := p.Put(second); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	// This conn has now been used twice (>= MaxUseCount); the next Get
+	// must evict it and hand out a fresh one instead.
+	third, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if third == first {
+		t.Fatalf("expected a fresh conn once MaxUseCount was reached")
+	}
+	if len(reasons) != 1 || reasons[0] != EvictUseCount {
+		t.Fatalf("OnEvict reasons = %v, want [EvictUseCount]", reasons)
+	}
+}
+
+// TestGenericPoolMaxConnLifetimeEvicts mirrors
+// TestGenericPoolMaxUseCountEvicts for MaxConnLifetime: a conn older than
+// MaxConnLifetime must be evicted on its next Get even though it is well
+// within MaxUseCount and not idle.
+func TestGenericPoolMaxConnLifetimeEvicts(t *testing.T) {
+	var reasons []EvictReason
+	p := NewGenericPool[*fakeConn](
+		&Config{
+			MaxConns:        1,
+			MaxIdleTime:     time.Minute,
+			MaxConnLifetime: 10 * time.Millisecond,
+			OnEvict: func(conn IConn, reason EvictReason) {
+				reasons = append(reasons, reason)
+			},
+		},
+		func() (*fakeConn, error) { return &fakeConn{}, nil },
+		nil,
+		func(*fakeConn) {},
+	)
+
+	first, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if err := p.Put(first); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	second, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if second == first {
+		t.Fatalf("expected a fresh conn once MaxConnLifetime elapsed")
+	}
+	if len(reasons) != 1 || reasons[0] != EvictLifetime {
+		t.Fatalf("OnEvict reasons = %v, want [EvictLifetime]", reasons)
+	}
+}
+
+// TestPoolUnhashableConnDoesNotPanic covers a gap the comparable
+// constraint alone can't close: Pool instantiates GenericPool[IConn], and
+// IConn satisfies comparable trivially regardless of the concrete type
+// boxed inside it. A value-type IConn implementation holding a slice
+// field used to panic the moment trackNew tried to use it as a map key.
+func TestPoolUnhashableConnDoesNotPanic(t *testing.T) {
+	p := NewPool(&Config{MaxConns: 1, MaxIdleTime: time.Minute}, func() (IConn, error) {
+		return badConnByValue{data: []byte("x")}, nil
+	})
+
+	conn, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if err := p.Put(conn); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := p.Get(); err != nil {
+		t.Fatalf("Get after Put: %v", err)
+	}
+}
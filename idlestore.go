@@ -0,0 +1,137 @@
+package pool
+
+import (
+	"sync"
+)
+
+// idleStore is a mutex-guarded, bounded store of idle items. By default it
+// behaves as a LIFO stack (most-recently-used served first), which keeps
+// the working set small under a shrinking load so stale items actually
+// reach reapStale instead of being perpetually recycled by a FIFO. Setting
+// fifo restores the original first-in-first-out behaviour.
+type idleStore[T any] struct {
+	mu    sync.Mutex
+	items []*genericItem[T]
+	cap   int
+	fifo  bool
+}
+
+func newIdleStore[T any](capacity int, fifo bool) *idleStore[T] {
+	return &idleStore[T]{
+		items: make([]*genericItem[T], 0, capacity),
+		cap:   capacity,
+		fifo:  fifo,
+	}
+}
+
+// push appends gi to the store, reporting false (without storing it) if
+// the store is already at capacity.
+func (s *idleStore[T]) push(gi *genericItem[T]) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.items) >= s.cap {
+		return false
+	}
+	s.items = append(s.items, gi)
+	return true
+}
+
+// pop removes and returns the next item to serve: the most recently
+// pushed one in LIFO mode, or the oldest pushed one in FIFO mode.
+func (s *idleStore[T]) pop() (*genericItem[T], bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.items)
+	if n == 0 {
+		return nil, false
+	}
+
+	if s.fifo {
+		gi := s.items[0]
+		s.items[0] = nil
+		s.items = s.items[1:]
+		return gi, true
+	}
+
+	gi := s.items[n-1]
+	s.items[n-1] = nil
+	s.items = s.items[:n-1]
+	return gi, true
+}
+
+func (s *idleStore[T]) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.items)
+}
+
+// reapIdle walks from the stale end of the store (the longest-idle items,
+// which accumulate at index 0 regardless of fifo/LIFO mode) and removes
+// every item for which shouldEvict returns true, stopping at the first
+// item it keeps or once minIdle items would remain. This early-break is
+// only sound for an idle-time predicate: idle items accumulate at index 0
+// because every push goes on the back and every pop (LIFO or FIFO) comes
+// off one end, so nothing idle can be hiding behind a fresh item. It
+// returns the removed items for the caller to destroy.
+func (s *idleStore[T]) reapIdle(shouldEvict func(*genericItem[T]) bool, minIdle int) []*genericItem[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := 0
+	for i < len(s.items) {
+		if len(s.items)-i <= minIdle {
+			break
+		}
+		if !shouldEvict(s.items[i]) {
+			break
+		}
+		i++
+	}
+	if i == 0 {
+		return nil
+	}
+
+	stale := append([]*genericItem[T]{}, s.items[:i]...)
+	s.items = s.items[i:]
+	return stale
+}
+
+// reapWhere scans every item in the store (unlike reapIdle, it cannot
+// stop at the first kept item) and removes each one for which shouldEvict
+// returns true, never taking the store below minIdle items. Use this for
+// predicates like lifetime or use-count that don't correlate with
+// position in the store the way idle time does.
+func (s *idleStore[T]) reapWhere(shouldEvict func(*genericItem[T]) bool, minIdle int) []*genericItem[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	budget := len(s.items) - minIdle
+	if budget <= 0 {
+		return nil
+	}
+
+	kept := make([]*genericItem[T], 0, len(s.items))
+	var removed []*genericItem[T]
+	for _, gi := range s.items {
+		if budget > 0 && shouldEvict(gi) {
+			removed = append(removed, gi)
+			budget--
+			continue
+		}
+		kept = append(kept, gi)
+	}
+	s.items = kept
+	return removed
+}
+
+// drain removes and returns every item currently in the store.
+func (s *idleStore[T]) drain() []*genericItem[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all := s.items
+	s.items = nil
+	return all
+}
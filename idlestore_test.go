@@ -0,0 +1,134 @@
+package pool
+
+import (
+	"testing"
+	"time"
+)
+
+func itemAt(val int, accessTime time.Time) *genericItem[int] {
+	return &genericItem[int]{val: val, accessTime: accessTime}
+}
+
+func TestIdleStorePushPopOrdering(t *testing.T) {
+	lifo := newIdleStore[int](3, false)
+	lifo.push(itemAt(1, time.Now()))
+	lifo.push(itemAt(2, time.Now()))
+	lifo.push(itemAt(3, time.Now()))
+	if gi, _ := lifo.pop(); gi.val != 3 {
+		t.Fatalf("LIFO pop = %d, want 3 (most recently pushed)", gi.val)
+	}
+
+	fifo := newIdleStore[int](3, true)
+	fifo.push(itemAt(1, time.Now()))
+	fifo.push(itemAt(2, time.Now()))
+	fifo.push(itemAt(3, time.Now()))
+	if gi, _ := fifo.pop(); gi.val != 1 {
+		t.Fatalf("FIFO pop = %d, want 1 (oldest pushed)", gi.val)
+	}
+}
+
+func TestIdleStorePushRejectsOverCapacity(t *testing.T) {
+	s := newIdleStore[int](1, false)
+	if !s.push(itemAt(1, time.Now())) {
+		t.Fatal("first push into an empty store should succeed")
+	}
+	if s.push(itemAt(2, time.Now())) {
+		t.Fatal("push past capacity should report false")
+	}
+}
+
+// TestIdleStoreReapIdleStopsAtFirstKept regression-tests reapIdle's
+// early-break optimization: it must only ever reap the stale prefix, and
+// must stop as soon as it finds a kept item even if a later item would
+// also be stale.
+func TestIdleStoreReapIdleStopsAtFirstKept(t *testing.T) {
+	s := newIdleStore[int](10, false)
+	now := time.Now()
+	s.push(itemAt(1, now.Add(-time.Hour))) // stale
+	s.push(itemAt(2, now))                 // fresh, interrupts the scan
+	s.push(itemAt(3, now.Add(-time.Hour))) // stale, but behind a kept item
+
+	stale := func(gi *genericItem[int]) bool {
+		return now.Sub(gi.accessTime) >= time.Minute
+	}
+	removed := s.reapIdle(stale, 0)
+	if len(removed) != 1 || removed[0].val != 1 {
+		t.Fatalf("reapIdle removed %v, want only item 1", removed)
+	}
+	if s.len() != 2 {
+		t.Fatalf("idle store len = %d, want 2 remaining", s.len())
+	}
+}
+
+// TestIdleStoreReapIdleRespectsMinIdle ensures the stale-prefix scan never
+// takes the store below minIdle, even when every item qualifies.
+func TestIdleStoreReapIdleRespectsMinIdle(t *testing.T) {
+	s := newIdleStore[int](10, false)
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		s.push(itemAt(i, now.Add(-time.Hour)))
+	}
+
+	allStale := func(*genericItem[int]) bool { return true }
+	removed := s.reapIdle(allStale, 2)
+	if len(removed) != 1 {
+		t.Fatalf("reapIdle removed %d items, want 1 (floor of 2 idle conns)", len(removed))
+	}
+	if s.len() != 2 {
+		t.Fatalf("idle store len = %d, want 2", s.len())
+	}
+}
+
+// TestIdleStoreReapWhereScansWholeStore is the direct regression test for
+// the bug fixed by b9bbc60: a predicate that doesn't correlate with
+// position (lifetime/use-count) must be reaped wherever in the store it
+// occurs, not just from the stale prefix reapIdle would find.
+func TestIdleStoreReapWhereScansWholeStore(t *testing.T) {
+	s := newIdleStore[int](10, false)
+	now := time.Now()
+	// Item 2 (in the middle) is the only one that should be evicted; 1
+	// and 3 are both freshly created and sit on either side of it, so a
+	// prefix-only scan (reapIdle) would never reach it.
+	s.push(&genericItem[int]{val: 1, accessTime: now, createdAt: now})
+	s.push(&genericItem[int]{val: 2, accessTime: now, createdAt: now.Add(-time.Hour)})
+	s.push(&genericItem[int]{val: 3, accessTime: now, createdAt: now})
+
+	expired := func(gi *genericItem[int]) bool {
+		return now.Sub(gi.createdAt) >= time.Minute
+	}
+	removed := s.reapWhere(expired, 0)
+	if len(removed) != 1 || removed[0].val != 2 {
+		t.Fatalf("reapWhere removed %v, want only item 2", removed)
+	}
+	if s.len() != 2 {
+		t.Fatalf("idle store len = %d, want 2 remaining", s.len())
+	}
+
+	remainingVals := map[int]bool{}
+	for n := s.len(); n > 0; n-- {
+		gi, _ := s.pop()
+		remainingVals[gi.val] = true
+	}
+	if !remainingVals[1] || !remainingVals[3] {
+		t.Fatalf("expected items 1 and 3 to survive, got %v", remainingVals)
+	}
+}
+
+// TestIdleStoreReapWhereRespectsMinIdle mirrors
+// TestIdleStoreReapIdleRespectsMinIdle for the full-scan path.
+func TestIdleStoreReapWhereRespectsMinIdle(t *testing.T) {
+	s := newIdleStore[int](10, false)
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		s.push(&genericItem[int]{val: i, accessTime: now, createdAt: now.Add(-time.Hour)})
+	}
+
+	allExpired := func(*genericItem[int]) bool { return true }
+	removed := s.reapWhere(allExpired, 2)
+	if len(removed) != 1 {
+		t.Fatalf("reapWhere removed %d items, want 1 (floor of 2 idle conns)", len(removed))
+	}
+	if s.len() != 2 {
+		t.Fatalf("idle store len = %d, want 2", s.len())
+	}
+}
@@ -0,0 +1,168 @@
+package pool
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MultiPoolConfigFunc builds the Config and NewConnFunc for a sub-pool the
+// first time MultiPool sees key. It is called under MultiPool's write lock,
+// once per sub-pool: again for the same key if the sweeper has evicted
+// that key's earlier sub-pool.
+type MultiPoolConfigFunc[K comparable] func(key K) (*Config, NewConnFunc)
+
+type subPool struct {
+	pool       *Pool
+	lastAccess int64 // unix nano, accessed atomically
+}
+
+// MultiPool is a pool of pools keyed by K, for clients that need one conn
+// pool per destination (e.g. per host:port, or per (host, port, tls-config)
+// tuple) instead of building that bookkeeping on top of Pool themselves.
+// Sub-pools are constructed lazily on first use and swept once they go
+// SubPoolIdleTimeout without a Get or Put.
+type MultiPool[K comparable] struct {
+	configFunc MultiPoolConfigFunc[K]
+
+	subPoolIdleTimeout time.Duration
+
+	mu    sync.RWMutex
+	pools map[K]*subPool
+
+	closed    int32
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewMultiPool creates a MultiPool. If subPoolIdleTimeout is zero, idle
+// sub-pools are never swept.
+func NewMultiPool[K comparable](configFunc MultiPoolConfigFunc[K], subPoolIdleTimeout time.Duration) *MultiPool[K] {
+	mp := &MultiPool[K]{
+		configFunc:         configFunc,
+		subPoolIdleTimeout: subPoolIdleTimeout,
+		pools:              make(map[K]*subPool),
+		done:               make(chan struct{}),
+	}
+
+	if subPoolIdleTimeout > 0 {
+		go mp.sweepRoutine()
+	}
+
+	return mp
+}
+
+// Get returns a conn from the sub-pool for key, creating that sub-pool on
+// first use. It returns ErrPoolClosed once Close has been called, instead
+// of resurrecting a sub-pool the sweeper can no longer reach.
+func (mp *MultiPool[K]) Get(ctx context.Context, key K) (IConn, error) {
+	sp, err := mp.subPool(key)
+	if err != nil {
+		return nil, err
+	}
+	atomic.StoreInt64(&sp.lastAccess, time.Now().UnixNano())
+	return sp.pool.GetContext(ctx)
+}
+
+// Put returns conn to the sub-pool for key. It is an error to Put to a key
+// that has never been Get (and so has no sub-pool); the conn is freed in
+// that case.
+func (mp *MultiPool[K]) Put(key K, conn IConn) error {
+	mp.mu.RLock()
+	sp, ok := mp.pools[key]
+	mp.mu.RUnlock()
+
+	if !ok {
+		conn.Free()
+		return ErrPoolClosed
+	}
+
+	atomic.StoreInt64(&sp.lastAccess, time.Now().UnixNano())
+	return sp.pool.Put(conn)
+}
+
+func (mp *MultiPool[K]) subPool(key K) (*subPool, error) {
+	mp.mu.RLock()
+	sp, ok := mp.pools[key]
+	mp.mu.RUnlock()
+	if ok {
+		return sp, nil
+	}
+
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	if mp.isClosed() {
+		return nil, ErrPoolClosed
+	}
+
+	if sp, ok := mp.pools[key]; ok {
+		return sp, nil
+	}
+
+	cfg, newConnFunc := mp.configFunc(key)
+	sp = &subPool{
+		pool:       NewPool(cfg, newConnFunc),
+		lastAccess: time.Now().UnixNano(),
+	}
+	mp.pools[key] = sp
+	return sp, nil
+}
+
+func (mp *MultiPool[K]) isClosed() bool {
+	return atomic.LoadInt32(&mp.closed) == 1
+}
+
+func (mp *MultiPool[K]) sweepRoutine() {
+	ticker := time.NewTicker(mp.subPoolIdleTimeout)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			mp.sweep()
+		case <-mp.done:
+			return
+		}
+	}
+}
+
+// sweep closes and evicts every sub-pool whose last Get/Put exceeds
+// SubPoolIdleTimeout.
+func (mp *MultiPool[K]) sweep() {
+	now := time.Now()
+
+	mp.mu.Lock()
+	var stale []*Pool
+	for key, sp := range mp.pools {
+		last := time.Unix(0, atomic.LoadInt64(&sp.lastAccess))
+		if now.Sub(last) >= mp.subPoolIdleTimeout {
+			stale = append(stale, sp.pool)
+			delete(mp.pools, key)
+		}
+	}
+	mp.mu.Unlock()
+
+	for _, p := range stale {
+		p.Close()
+	}
+}
+
+// Close stops the sweeper and closes every sub-pool.
+func (mp *MultiPool[K]) Close() error {
+	mp.closeOnce.Do(func() {
+		atomic.StoreInt32(&mp.closed, 1)
+		close(mp.done)
+
+		mp.mu.Lock()
+		pools := mp.pools
+		mp.pools = make(map[K]*subPool)
+		mp.mu.Unlock()
+
+		for _, sp := range pools {
+			sp.pool.Close()
+		}
+	})
+	return nil
+}
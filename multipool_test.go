@@ -0,0 +1,93 @@
+package pool
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newFakeMultiPool(subPoolIdleTimeout time.Duration) *MultiPool[string] {
+	return NewMultiPool[string](func(key string) (*Config, NewConnFunc) {
+		return &Config{MaxConns: 1, MaxIdleTime: time.Minute}, func() (IConn, error) {
+			return &fakeConn{}, nil
+		}
+	}, subPoolIdleTimeout)
+}
+
+func TestMultiPoolLazyCreatesOneSubPoolPerKey(t *testing.T) {
+	mp := newFakeMultiPool(0)
+	defer mp.Close()
+
+	a, err := mp.Get(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("Get a: %v", err)
+	}
+	if err := mp.Put("a", a); err != nil {
+		t.Fatalf("Put a: %v", err)
+	}
+
+	again, err := mp.Get(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("Get a again: %v", err)
+	}
+	if again != a {
+		t.Fatalf("expected the same sub-pool to hand back the conn it was Put")
+	}
+
+	if _, err := mp.Get(context.Background(), "b"); err != nil {
+		t.Fatalf("Get b: %v", err)
+	}
+}
+
+func TestMultiPoolPutUnknownKeyFreesConn(t *testing.T) {
+	mp := newFakeMultiPool(0)
+	defer mp.Close()
+
+	c := &fakeConn{}
+	if err := mp.Put("never-got", c); err != ErrPoolClosed {
+		t.Fatalf("Put unknown key: expected ErrPoolClosed, got %v", err)
+	}
+	if !c.freed {
+		t.Fatal("expected the conn to be freed when Put to an unknown key")
+	}
+}
+
+func TestMultiPoolSweepsIdleSubPools(t *testing.T) {
+	mp := newFakeMultiPool(20 * time.Millisecond)
+	defer mp.Close()
+
+	conn, err := mp.Get(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if err := mp.Put("a", conn); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	// The sub-pool for "a" should have been swept and closed; Put to it
+	// now behaves like an unknown key.
+	if err := mp.Put("a", &fakeConn{}); err != ErrPoolClosed {
+		t.Fatalf("Put after sweep: expected ErrPoolClosed, got %v", err)
+	}
+}
+
+// TestMultiPoolCloseRejectsGetInsteadOfLeakingSubPool is the regression
+// test for the fix that added MultiPool.closed: before it, a Get after
+// Close silently created a new sub-pool that the (already-exited)
+// sweeper could never reach again.
+func TestMultiPoolCloseRejectsGetInsteadOfLeakingSubPool(t *testing.T) {
+	mp := newFakeMultiPool(0)
+
+	if err := mp.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if _, err := mp.Get(context.Background(), "new-key"); err != ErrPoolClosed {
+		t.Fatalf("Get after Close: expected ErrPoolClosed, got %v", err)
+	}
+	if len(mp.pools) != 0 {
+		t.Fatalf("Get after Close must not create a sub-pool, found %d", len(mp.pools))
+	}
+}
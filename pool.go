@@ -1,6 +1,7 @@
 package pool
 
 import (
+	"context"
 	"errors"
 	"time"
 )
@@ -15,110 +16,126 @@ type Config struct {
 	KeepAliveInterval time.Duration
 
 	KeepAliveFunc func(conn IConn) error
-}
 
-type poolItem struct {
-	conn       IConn
-	accessTime time.Time
+	// WaitTimeout bounds how long GetContext blocks for a free conn once
+	// MaxConns is reached. Zero means wait indefinitely (subject to ctx).
+	WaitTimeout time.Duration
+
+	// MaxWaiters bounds the number of goroutines allowed to queue inside
+	// GetContext at once. Zero means unbounded.
+	MaxWaiters int
+
+	// ValidateFunc, if set, is invoked on every conn pulled off the idle
+	// list during Get/GetContext. A returned error frees that conn and
+	// tries the next idle one, up to MaxValidateAttempts.
+	ValidateFunc func(conn IConn) error
+
+	// MaxValidateAttempts bounds how many idle conns Get/GetContext may
+	// discard (rejected by ValidateFunc, or past MaxConnLifetime or
+	// MaxUseCount) before falling back to NewItemFunc. Zero means only the
+	// first idle conn is tried.
+	MaxValidateAttempts int
+
+	// ReapOnBorrow makes Get/GetContext keep scanning past a stale idle
+	// conn (one older than MaxIdleTime) instead of stopping at the first
+	// one, discarding every stale conn it encounters along the way.
+	ReapOnBorrow bool
+
+	// PoolFIFO serves idle conns first-in-first-out instead of the
+	// default LIFO (most-recently-used first) ordering.
+	PoolFIFO bool
+
+	// MinIdleConns is the floor the background stale reaper keeps: it
+	// never reaps an idle conn that would take the idle count below this.
+	MinIdleConns int
+
+	// MaxConnLifetime, if set, recycles a conn once it has existed this
+	// long regardless of idle time. Useful for conns behind a load
+	// balancer or with expiring TLS sessions.
+	MaxConnLifetime time.Duration
+
+	// MaxUseCount, if set, recycles a conn once it has been borrowed this
+	// many times (e.g. to bound prepared-statement leaks).
+	MaxUseCount uint64
+
+	// OnEvict, if set, is called whenever a conn is removed from the
+	// pool instead of being handed back out, with the reason why.
+	OnEvict func(conn IConn, reason EvictReason)
 }
 
 type NewConnFunc func() (IConn, error)
 
+// Stats is a snapshot of pool occupancy.
+type Stats struct {
+	TotalConns int32
+	IdleConns  int32
+	Waiting    int32
+	Timeouts   uint64
+}
+
+var ErrPoolIsFull = errors.New("pool is full")
+
+// ErrPoolExhausted is returned by GetContext when Config.MaxWaiters is set
+// and that many callers are already blocked waiting for a conn.
+var ErrPoolExhausted = errors.New("pool: too many waiters")
+
+// ErrPoolClosed is returned by Get, GetContext and Put once Close has been
+// called.
+var ErrPoolClosed = errors.New("pool: closed")
+
+// Pool is a conn pool built on top of GenericPool[IConn]; it is kept as a
+// thin IConn/NewConnFunc-based wrapper so existing callers are unaffected
+// by the generic pool added in generic.go.
 type Pool struct {
-	config *Config
-	conns  chan *poolItem
+	gp *GenericPool[IConn]
 
 	NewItemFunc NewConnFunc
 }
 
-var ErrPoolIsFull = errors.New("pool is full")
-
 func NewPool(config *Config, newConnFunc NewConnFunc) *Pool {
 	p := &Pool{
-		config:      config,
-		conns:       make(chan *poolItem, config.MaxConns),
 		NewItemFunc: newConnFunc,
 	}
-
-	if config.KeepAliveInterval > 0 && config.KeepAliveFunc != nil {
-		go p.keepAliveRoutine()
-	}
-
+	p.gp = NewGenericPool[IConn](config, newConnFunc, nil, func(conn IConn) {
+		conn.Free()
+	})
 	return p
 }
 
 func (p *Pool) Get() (IConn, error) {
-	pi := p.get()
-	if pi != nil {
-		if time.Now().Sub(pi.accessTime) < p.config.MaxIdleTime {
-			return pi.conn, nil
-		}
-		pi.conn.Free()
-	}
-	return p.NewItemFunc()
+	return p.gp.Get()
 }
 
-func (p *Pool) Put(conn IConn) error {
-	pi := &poolItem{
-		conn:       conn,
-		accessTime: time.Now(),
-	}
-
-	notFull := p.put(pi)
-	if notFull {
-		return nil
-	}
-
-	conn.Free()
-
-	return ErrPoolIsFull
+// GetContext enforces a true hard cap of Config.MaxConns live conns: once
+// that many are outstanding and no idle item is available, it blocks until
+// a Put frees one up, ctx is cancelled, or Config.WaitTimeout elapses. If
+// Config.MaxWaiters is set and already reached, it fails fast with
+// ErrPoolExhausted instead of queuing another waiter.
+func (p *Pool) GetContext(ctx context.Context) (IConn, error) {
+	return p.gp.GetContext(ctx)
 }
 
-func (p *Pool) get() *poolItem {
-	select {
-	case pi := <-p.conns:
-		return pi
-	default:
-	}
-	return nil
+func (p *Pool) Put(conn IConn) error {
+	return p.gp.Put(conn)
 }
 
-func (p *Pool) put(pi *poolItem) bool {
-	select {
-	case p.conns <- pi:
-		return true
-	default:
-	}
-	return false
+// Stats returns a snapshot of current pool occupancy.
+func (p *Pool) Stats() Stats {
+	return p.gp.Stats()
 }
 
-func (p *Pool) keepAliveRoutine() {
-	ticker := time.NewTicker(p.config.KeepAliveInterval)
-
-	for {
-		select {
-		case <-ticker.C:
-			p.keepAlive()
-		}
-	}
+// CloseTimeout bounds how long Close waits for in-flight GetContext
+// waiters to unblock before returning. Zero (the default) means Close
+// waits indefinitely.
+func (p *Pool) CloseTimeout(d time.Duration) {
+	p.gp.CloseTimeout(d)
 }
 
-func (p *Pool) keepAlive() {
-	maxConns := len(p.conns)
-
-	for i := 0; i < maxConns; i++ {
-		pi := p.get()
-		if pi != nil {
-			if time.Now().Sub(pi.accessTime) < p.config.KeepAliveInterval {
-				err := p.config.KeepAliveFunc(pi.conn)
-				if err != nil {
-					if p.put(pi) {
-						continue
-					}
-				}
-			}
-
-			pi.conn.Free()
-		}
-	}
+// Close stops the keepalive goroutine, drains and frees every idle conn,
+// and causes subsequent Get, GetContext and Put calls to fail with
+// ErrPoolClosed. It is safe to call Close more than once. If waiters are
+// blocked in GetContext, Close waits for them to unblock, bounded by
+// CloseTimeout if one was set.
+func (p *Pool) Close() error {
+	return p.gp.Close()
 }
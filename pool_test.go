@@ -0,0 +1,162 @@
+package pool
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeConn struct {
+	freed bool
+}
+
+func (c *fakeConn) Free() {
+	c.freed = true
+}
+
+func newFakeConnPool(cfg *Config) *Pool {
+	return NewPool(cfg, func() (IConn, error) {
+		return &fakeConn{}, nil
+	})
+}
+
+func TestGetContextBlocksUntilPut(t *testing.T) {
+	p := newFakeConnPool(&Config{MaxConns: 1, MaxIdleTime: time.Minute})
+
+	conn, err := p.GetContext(context.Background())
+	if err != nil {
+		t.Fatalf("first GetContext: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c2, err := p.GetContext(context.Background())
+		if err != nil {
+			t.Errorf("second GetContext: %v", err)
+			return
+		}
+		if c2 != conn {
+			t.Errorf("expected second GetContext to receive the returned conn")
+		}
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("second GetContext returned before Put, cap was not enforced")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if err := p.Put(conn); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("second GetContext never unblocked after Put")
+	}
+}
+
+func TestGetContextCancelUnblocksWaiter(t *testing.T) {
+	p := newFakeConnPool(&Config{MaxConns: 1, MaxIdleTime: time.Minute})
+
+	if _, err := p.GetContext(context.Background()); err != nil {
+		t.Fatalf("GetContext: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := p.GetContext(ctx)
+		errCh <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errCh:
+		if err != context.Canceled {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("GetContext never unblocked on cancel")
+	}
+}
+
+func TestGetContextWaitTimeout(t *testing.T) {
+	p := newFakeConnPool(&Config{
+		MaxConns:    1,
+		MaxIdleTime: time.Minute,
+		WaitTimeout: 20 * time.Millisecond,
+	})
+
+	if _, err := p.GetContext(context.Background()); err != nil {
+		t.Fatalf("GetContext: %v", err)
+	}
+
+	_, err := p.GetContext(context.Background())
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	if got := p.Stats().Timeouts; got != 1 {
+		t.Fatalf("Stats().Timeouts = %d, want 1", got)
+	}
+}
+
+func TestGetContextMaxWaiters(t *testing.T) {
+	p := newFakeConnPool(&Config{MaxConns: 1, MaxIdleTime: time.Minute, MaxWaiters: 1})
+
+	if _, err := p.GetContext(context.Background()); err != nil {
+		t.Fatalf("GetContext: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// Occupies the single waiter slot until the test ends.
+		p.GetContext(context.Background())
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	_, err := p.GetContext(context.Background())
+	if err != ErrPoolExhausted {
+		t.Fatalf("expected ErrPoolExhausted, got %v", err)
+	}
+}
+
+func TestCloseUnblocksWaiters(t *testing.T) {
+	p := newFakeConnPool(&Config{MaxConns: 1, MaxIdleTime: time.Minute})
+
+	if _, err := p.GetContext(context.Background()); err != nil {
+		t.Fatalf("GetContext: %v", err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := p.GetContext(context.Background())
+		errCh <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	select {
+	case err := <-errCh:
+		if err != ErrPoolClosed {
+			t.Fatalf("expected ErrPoolClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close never unblocked the waiter")
+	}
+
+	if _, err := p.Get(); err != ErrPoolClosed {
+		t.Fatalf("Get after Close: expected ErrPoolClosed, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package pool
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errValidateRejected = errors.New("rejected")
+
+// TestValidateFuncRejectsIdleConn covers the basic ValidateFunc contract:
+// a conn that fails validation is discarded instead of handed back out.
+func TestValidateFuncRejectsIdleConn(t *testing.T) {
+	var destroyed int32
+	p := NewGenericPool[*fakeConn](
+		&Config{
+			MaxConns:    2,
+			MaxIdleTime: time.Minute,
+			ValidateFunc: func(conn IConn) error {
+				return errValidateRejected
+			},
+		},
+		func() (*fakeConn, error) { return &fakeConn{}, nil },
+		nil,
+		func(*fakeConn) { destroyed++ },
+	)
+
+	first, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if err := p.Put(first); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	second, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if second == first {
+		t.Fatalf("expected ValidateFunc to reject the idle conn and hand back a fresh one")
+	}
+	if destroyed != 1 {
+		t.Fatalf("destroyed = %d, want 1", destroyed)
+	}
+}
+
+// TestMaxValidateAttemptsBoundsRejections checks that Get gives up on the
+// idle list after MaxValidateAttempts rejections and falls back to a
+// fresh conn rather than trying forever.
+func TestMaxValidateAttemptsBoundsRejections(t *testing.T) {
+	var validated int32
+	p := NewGenericPool[*fakeConn](
+		&Config{
+			MaxConns:            3,
+			MaxIdleTime:         time.Minute,
+			MaxValidateAttempts: 2,
+			ValidateFunc: func(conn IConn) error {
+				validated++
+				return errValidateRejected
+			},
+		},
+		func() (*fakeConn, error) { return &fakeConn{}, nil },
+		nil,
+		func(*fakeConn) {},
+	)
+
+	a, _ := p.Get()
+	b, _ := p.Get()
+	p.Put(a)
+	p.Put(b)
+
+	if _, err := p.Get(); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if validated != 2 {
+		t.Fatalf("ValidateFunc called %d times, want 2 (MaxValidateAttempts)", validated)
+	}
+}
+
+// TestReapOnBorrowSkipsStaleConnsToFindLive verifies that, with
+// ReapOnBorrow set, Get keeps scanning past stale idle conns (discarding
+// each one) instead of stopping at the first stale conn it finds.
+func TestReapOnBorrowSkipsStaleConnsToFindLive(t *testing.T) {
+	var destroyed int32
+	p := NewGenericPool[*fakeConn](
+		&Config{
+			MaxConns:     3,
+			MaxIdleTime:  time.Minute,
+			ReapOnBorrow: true,
+		},
+		func() (*fakeConn, error) { return &fakeConn{}, nil },
+		nil,
+		func(*fakeConn) { destroyed++ },
+	)
+
+	live, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if err := p.Put(live); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	// Push a stale item on top of the now-idle live conn; with the
+	// default LIFO ordering it is popped first, and ReapOnBorrow must
+	// make Get discard it and keep scanning down to the live conn
+	// instead of giving up and returning a fresh one.
+	stale := &fakeConn{}
+	p.conns.push(&genericItem[*fakeConn]{val: stale, accessTime: time.Now().Add(-time.Hour)})
+
+	got, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got != live {
+		t.Fatalf("expected Get to reap the stale conn and return the live one, got a fresh conn instead")
+	}
+	if destroyed != 1 {
+		t.Fatalf("destroyed = %d, want 1 (the stale conn)", destroyed)
+	}
+}